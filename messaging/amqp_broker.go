@@ -0,0 +1,120 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/streadway/amqp"
+)
+
+// AMQPConfig holds the connection details for the AMQP/RabbitMQ Broker
+// implementation.
+type AMQPConfig struct {
+	URL      string `mapstructure:"url"`
+	Exchange string `mapstructure:"exchange"`
+}
+
+// amqpBroker adapts an AMQP connection/channel pair to the Broker interface,
+// using a topic exchange so Subject behaves like a NATS subject.
+type amqpBroker struct {
+	conn *amqp.Connection
+	ch   *amqp.Channel
+	cfg  *AMQPConfig
+	log  *logrus.Entry
+}
+
+func newAMQPBroker(cfg *AMQPConfig, log *logrus.Entry) (*amqpBroker, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no amqp config provided")
+	}
+
+	conn, err := amqp.Dial(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial amqp broker: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open amqp channel: %w", err)
+	}
+
+	if err := ch.ExchangeDeclare(cfg.Exchange, "topic", true, false, false, false, nil); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare amqp exchange %s: %w", cfg.Exchange, err)
+	}
+
+	return &amqpBroker{conn: conn, ch: ch, cfg: cfg, log: log.WithField("component", "amqp-broker")}, nil
+}
+
+func (b *amqpBroker) Publish(subject string, data []byte) error {
+	return b.ch.Publish(b.cfg.Exchange, subject, false, false, amqp.Publishing{
+		Body: data,
+	})
+}
+
+func (b *amqpBroker) Subscribe(subject string, handler MsgHandler) (Subscription, error) {
+	return b.consume(subject, "", handler)
+}
+
+func (b *amqpBroker) QueueSubscribe(subject, queue string, handler MsgHandler) (Subscription, error) {
+	return b.consume(subject, queue, handler)
+}
+
+func (b *amqpBroker) consume(subject, queue string, handler MsgHandler) (Subscription, error) {
+	q, err := b.ch.QueueDeclare(queue, false, true, queue == "", false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare amqp queue for %s: %w", subject, err)
+	}
+
+	if err := b.ch.QueueBind(q.Name, subject, b.cfg.Exchange, false, nil); err != nil {
+		return nil, fmt.Errorf("failed to bind amqp queue to %s: %w", subject, err)
+	}
+
+	deliveries, err := b.ch.Consume(q.Name, "", true, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume amqp queue %s: %w", q.Name, err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case d, ok := <-deliveries:
+				if !ok {
+					return
+				}
+				handler(&Message{Subject: d.RoutingKey, Reply: d.ReplyTo, Data: d.Body})
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return &amqpSubscription{ch: b.ch, queue: q.Name, done: done}, nil
+}
+
+func (b *amqpBroker) Request(subject string, data []byte, timeout time.Duration) (*Message, error) {
+	return nil, fmt.Errorf("amqp broker does not yet support request/reply")
+}
+
+func (b *amqpBroker) Close() error {
+	if err := b.ch.Close(); err != nil {
+		b.log.WithError(err).Warn("Error closing amqp channel")
+	}
+	return b.conn.Close()
+}
+
+type amqpSubscription struct {
+	ch    *amqp.Channel
+	queue string
+	done  chan struct{}
+}
+
+func (s *amqpSubscription) Unsubscribe() error {
+	close(s.done)
+	_, err := s.ch.QueueDelete(s.queue, false, false, false)
+	return err
+}
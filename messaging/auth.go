@@ -0,0 +1,95 @@
+package messaging
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nats"
+)
+
+// AuthConfig selects and configures one of the NATS authentication
+// mechanisms. Exactly one of User/Password, Token, CredsFile, or NKeyFile
+// should be set - Validate enforces this before ConnectToNats builds the
+// connection options.
+type AuthConfig struct {
+	User     string `mapstructure:"user"`
+	Password string `mapstructure:"password"`
+	Token    string `mapstructure:"token"`
+	// CredsFile is the path to a decentralized JWT + NKey credentials file,
+	// as produced by `nsc generate creds`.
+	CredsFile string `mapstructure:"creds_file"`
+	// NKeyFile is the path to a raw NKey seed file, used when a full creds
+	// file isn't available (e.g. mutual auth against a BOSH director).
+	NKeyFile string `split_words:"true" mapstructure:"nkey_file"`
+}
+
+// Validate ensures exactly one auth mode has been configured.
+func (a *AuthConfig) Validate() error {
+	modes := 0
+	if a.User != "" || a.Password != "" {
+		modes++
+	}
+	if a.Token != "" {
+		modes++
+	}
+	if a.CredsFile != "" {
+		modes++
+	}
+	if a.NKeyFile != "" {
+		modes++
+	}
+
+	if modes == 0 {
+		return fmt.Errorf("auth config must set one of user/password, token, creds_file, or nkey_file")
+	}
+	if modes > 1 {
+		return fmt.Errorf("auth config must set exactly one auth mode, found %d", modes)
+	}
+
+	return nil
+}
+
+// options builds the nats.Option(s) implied by the configured auth mode.
+func (a *AuthConfig) options() ([]nats.Option, error) {
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case a.User != "" || a.Password != "":
+		return []nats.Option{nats.UserInfo(a.User, a.Password)}, nil
+	case a.Token != "":
+		return []nats.Option{nats.Token(a.Token)}, nil
+	case a.CredsFile != "":
+		return []nats.Option{nats.UserCredentials(a.CredsFile)}, nil
+	case a.NKeyFile != "":
+		opt, err := nats.NkeyOptionFromSeed(a.NKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load nkey seed file %s: %w", a.NKeyFile, err)
+		}
+		return []nats.Option{opt}, nil
+	}
+
+	return nil, nil
+}
+
+// Fields returns a logrus-friendly view of the configured auth mode with
+// secrets redacted.
+func (a *AuthConfig) Fields() map[string]interface{} {
+	f := map[string]interface{}{}
+
+	switch {
+	case a.User != "" || a.Password != "":
+		f["auth_mode"] = "user"
+		f["user"] = a.User
+	case a.Token != "":
+		f["auth_mode"] = "token"
+	case a.CredsFile != "":
+		f["auth_mode"] = "creds_file"
+		f["creds_file"] = a.CredsFile
+	case a.NKeyFile != "":
+		f["auth_mode"] = "nkey"
+		f["nkey_file"] = a.NKeyFile
+	}
+
+	return f
+}
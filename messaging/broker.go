@@ -0,0 +1,133 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats"
+	"github.com/sirupsen/logrus"
+)
+
+// Message is a transport-agnostic envelope delivered to Broker subscribers,
+// decoupling handler code from the underlying driver's message type.
+type Message struct {
+	Subject string
+	Reply   string
+	Data    []byte
+}
+
+// MsgHandler is called for every message delivered to a Broker subscription.
+type MsgHandler func(msg *Message)
+
+// Subscription represents an active Broker subscription that can be torn
+// down independently of the underlying connection.
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Broker is a transport-agnostic pub/sub abstraction. Services should depend
+// on this interface rather than a concrete driver so the underlying message
+// bus can be swapped via config without touching call sites.
+type Broker interface {
+	Publish(subject string, data []byte) error
+	Subscribe(subject string, handler MsgHandler) (Subscription, error)
+	QueueSubscribe(subject, queue string, handler MsgHandler) (Subscription, error)
+	Request(subject string, data []byte, timeout time.Duration) (*Message, error)
+	Close() error
+}
+
+// Driver selects which Broker implementation Connect builds.
+type Driver string
+
+const (
+	DriverNats Driver = "nats"
+	DriverAMQP Driver = "amqp"
+)
+
+// BrokerConfig selects a Driver and holds the driver-specific config needed
+// to connect it. Only the section matching Driver needs to be populated.
+type BrokerConfig struct {
+	Driver Driver      `mapstructure:"driver"`
+	Nats   *NatsConfig `mapstructure:"nats"`
+	AMQP   *AMQPConfig `mapstructure:"amqp"`
+}
+
+// Connect dispatches on config.Driver and returns the corresponding Broker
+// implementation, defaulting to the NATS driver for backwards compatibility
+// with configs that predate this option.
+func Connect(config *BrokerConfig, log *logrus.Entry) (Broker, error) {
+	if config == nil {
+		return nil, fmt.Errorf("no broker config provided")
+	}
+
+	switch config.Driver {
+	case "", DriverNats:
+		nc, handle, err := ConfigureNatsConnection(config.Nats, log)
+		if err != nil {
+			return nil, err
+		}
+		return newNatsBroker(nc, handle), nil
+	case DriverAMQP:
+		return newAMQPBroker(config.AMQP, log)
+	default:
+		return nil, fmt.Errorf("unknown broker driver %q", config.Driver)
+	}
+}
+
+// natsBroker adapts a *nats.Conn to the Broker interface.
+type natsBroker struct {
+	nc     *nats.Conn
+	handle *ConnHandle
+}
+
+func newNatsBroker(nc *nats.Conn, handle *ConnHandle) *natsBroker {
+	return &natsBroker{nc: nc, handle: handle}
+}
+
+func (b *natsBroker) Publish(subject string, data []byte) error {
+	return b.nc.Publish(subject, data)
+}
+
+func (b *natsBroker) Subscribe(subject string, handler MsgHandler) (Subscription, error) {
+	sub, err := b.nc.Subscribe(subject, natsMsgHandler(handler))
+	if err != nil {
+		return nil, err
+	}
+	if b.handle != nil {
+		b.handle.TrackSubscription(sub)
+	}
+	return sub, nil
+}
+
+func (b *natsBroker) QueueSubscribe(subject, queue string, handler MsgHandler) (Subscription, error) {
+	sub, err := b.nc.QueueSubscribe(subject, queue, natsMsgHandler(handler))
+	if err != nil {
+		return nil, err
+	}
+	if b.handle != nil {
+		b.handle.TrackSubscription(sub)
+	}
+	return sub, nil
+}
+
+func (b *natsBroker) Request(subject string, data []byte, timeout time.Duration) (*Message, error) {
+	msg, err := b.nc.Request(subject, data, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &Message{Subject: msg.Subject, Reply: msg.Reply, Data: msg.Data}, nil
+}
+
+func (b *natsBroker) Close() error {
+	if b.handle != nil {
+		b.handle.Close()
+	}
+	b.nc.Close()
+	return nil
+}
+
+func natsMsgHandler(handler MsgHandler) nats.MsgHandler {
+	return func(msg *nats.Msg) {
+		handler(&Message{Subject: msg.Subject, Reply: msg.Reply, Data: msg.Data})
+	}
+}
@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"sync"
+
+	"github.com/nats-io/nats"
+)
+
+// ConnHandle is returned alongside the *nats.Conn from ConfigureNatsConnection
+// and ConnectToNats. It owns everything about the connection that outlives a
+// single call - background goroutines (metrics reporting, discovery refresh),
+// tracked subscriptions, and the embedded server when NatsConfig.Embedded is
+// set - so callers, tests in particular, have a single place to tear all of
+// it down.
+type ConnHandle struct {
+	// Embedded is set when the connection was made to an in-process server
+	// started per NatsConfig.Embedded. Close shuts it down, so callers don't
+	// need to reach for it directly unless they want its ClientURL.
+	Embedded *EmbeddedServer
+
+	mu        sync.Mutex
+	subs      []*nats.Subscription
+	stopFuncs []func()
+}
+
+func newConnHandle() *ConnHandle {
+	return &ConnHandle{}
+}
+
+// TrackSubscription registers sub so it shows up in connection metrics
+// reported via MetricsConfig, and returns it unchanged for chaining.
+func (h *ConnHandle) TrackSubscription(sub *nats.Subscription) *nats.Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs = append(h.subs, sub)
+	return sub
+}
+
+// Subscriptions returns a snapshot of the subscriptions tracked so far.
+func (h *ConnHandle) Subscriptions() []*nats.Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := make([]*nats.Subscription, len(h.subs))
+	copy(subs, h.subs)
+	return subs
+}
+
+func (h *ConnHandle) addStopFunc(stop func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopFuncs = append(h.stopFuncs, stop)
+}
+
+// Close stops every background goroutine started on behalf of this
+// connection (metrics reporting, discovery refresh) and shuts down the
+// embedded server, if any. It does not close the underlying *nats.Conn -
+// callers still own that separately.
+func (h *ConnHandle) Close() {
+	h.mu.Lock()
+	stopFuncs := h.stopFuncs
+	h.stopFuncs = nil
+	h.mu.Unlock()
+
+	for _, stop := range stopFuncs {
+		stop()
+	}
+
+	if h.Embedded != nil {
+		h.Embedded.Shutdown()
+	}
+}
@@ -0,0 +1,107 @@
+package messaging
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// defaultDiscoveryRefresh is how often StartDiscoveryDriftLogger re-polls
+// discovery when NatsConfig.DiscoveryRefresh is unset.
+const defaultDiscoveryRefresh = time.Minute
+
+// StartDiscoveryDriftLogger periodically re-resolves config.DiscoveryName and
+// logs any servers added or removed since the last poll (or since the
+// initial ConnectToNats call).
+//
+// Known gap: this does NOT apply the change to the live *nats.Conn. The
+// nats-io/nats client this module is pinned to builds its server pool once
+// inside Connect and never reads it again afterward, and that client
+// exposes no public API to reload it - a prior attempt at this set
+// nc.Opts.Servers directly, which compiled but silently did nothing (and
+// raced with the client's own reads of that field). Until this module
+// migrates to nats-io/nats.go (which can reload server lists), the best
+// this can do is log the drift so an operator can restart the affected
+// service to pick up the new servers. It is a no-op, returning a no-op stop
+// func, when config.DiscoveryName is unset.
+func StartDiscoveryDriftLogger(config *NatsConfig, log *logrus.Entry) func() {
+	if config.DiscoveryName == "" {
+		return func() {}
+	}
+
+	refreshLog := log.WithFields(logrus.Fields{
+		"component": "discovery-drift-logger",
+		"service":   config.DiscoveryName,
+	})
+	refreshLog.Warn("Discovery drift logging is active but cannot reload this connection's server list live; restart the service to pick up server changes")
+
+	interval := config.DiscoveryRefresh
+	if interval == 0 {
+		interval = defaultDiscoveryRefresh
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		current := append([]string{}, config.Servers...)
+
+		for {
+			select {
+			case <-ticker.C:
+				servers, err := discoverNatsURLs(config.DiscoveryName)
+				if err != nil {
+					refreshLog.WithError(err).Warn("Failed to refresh nats discovery")
+					continue
+				}
+
+				added, removed := diffServers(current, servers)
+				if len(added) == 0 && len(removed) == 0 {
+					continue
+				}
+
+				refreshLog.WithFields(logrus.Fields{
+					"added":   strings.Join(added, ","),
+					"removed": strings.Join(removed, ","),
+				}).Info("Nats discovery servers changed")
+
+				current = servers
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+// diffServers returns the servers present in next but not prev (added) and
+// the servers present in prev but not next (removed).
+func diffServers(prev, next []string) (added, removed []string) {
+	prevSet := make(map[string]bool, len(prev))
+	for _, s := range prev {
+		prevSet[s] = true
+	}
+	nextSet := make(map[string]bool, len(next))
+	for _, s := range next {
+		nextSet[s] = true
+	}
+
+	for _, s := range next {
+		if !prevSet[s] {
+			added = append(added, s)
+		}
+	}
+	for _, s := range prev {
+		if !nextSet[s] {
+			removed = append(removed, s)
+		}
+	}
+
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats"
+	natsserver "github.com/nats-io/nats-server/v2/server"
+	"github.com/sirupsen/logrus"
+)
+
+// EmbeddedConfig configures an in-process NATS server, letting services and
+// tests run against a real broker without a docker/nats sidecar.
+type EmbeddedConfig struct {
+	Port      int    `mapstructure:"port"` // -1 picks a random free port; 0 uses the default nats port
+	JetStream bool   `mapstructure:"jetstream"`
+	StoreDir  string `mapstructure:"store_dir"`
+}
+
+// embeddedReadyTimeout bounds how long EmbeddedServer waits for the
+// in-process server to come up before giving up.
+const embeddedReadyTimeout = 4 * time.Second
+
+// EmbeddedServer wraps an in-process *nats-server.Server. Call Shutdown when
+// done with it, typically in a test's cleanup.
+type EmbeddedServer struct {
+	srv *natsserver.Server
+	log *logrus.Entry
+}
+
+// NewEmbeddedServer starts an in-process NATS server per config and blocks
+// until it is ready to accept connections.
+func NewEmbeddedServer(config *EmbeddedConfig, log *logrus.Entry) (*EmbeddedServer, error) {
+	if config == nil {
+		return nil, fmt.Errorf("no embedded server config provided")
+	}
+
+	opts := &natsserver.Options{
+		Port:      config.Port,
+		JetStream: config.JetStream,
+		StoreDir:  config.StoreDir,
+	}
+
+	srv, err := natsserver.NewServer(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create embedded nats server: %w", err)
+	}
+
+	srv.SetLoggerV2(newEmbeddedLogger(log), false, false, false)
+
+	go srv.Start()
+	if !srv.ReadyForConnections(embeddedReadyTimeout) {
+		srv.Shutdown()
+		return nil, fmt.Errorf("embedded nats server did not become ready within %s", embeddedReadyTimeout)
+	}
+
+	return &EmbeddedServer{srv: srv, log: log.WithField("component", "embedded-nats")}, nil
+}
+
+// ClientURL returns the URL clients should dial to reach this server.
+func (e *EmbeddedServer) ClientURL() string {
+	return e.srv.ClientURL()
+}
+
+// Connect dials this embedded server, layering any extra options on top of
+// the required URL.
+func (e *EmbeddedServer) Connect(opts ...nats.Option) (*nats.Conn, error) {
+	return nats.Connect(e.ClientURL(), opts...)
+}
+
+// Shutdown stops the embedded server and waits for it to fully exit.
+func (e *EmbeddedServer) Shutdown() {
+	e.srv.Shutdown()
+	e.srv.WaitForShutdown()
+	e.log.Debug("Embedded nats server shut down")
+}
+
+// embeddedLogger adapts a logrus.Entry to the nats-server Logger interface
+// so embedded server logs flow through the same pipeline as the rest of the
+// service.
+type embeddedLogger struct {
+	log *logrus.Entry
+}
+
+func newEmbeddedLogger(log *logrus.Entry) *embeddedLogger {
+	return &embeddedLogger{log: log.WithField("component", "embedded-nats-server")}
+}
+
+func (l *embeddedLogger) Noticef(format string, v ...interface{}) { l.log.Infof(format, v...) }
+func (l *embeddedLogger) Warnf(format string, v ...interface{})   { l.log.Warnf(format, v...) }
+func (l *embeddedLogger) Errorf(format string, v ...interface{})  { l.log.Errorf(format, v...) }
+func (l *embeddedLogger) Fatalf(format string, v ...interface{})  { l.log.Fatalf(format, v...) }
+func (l *embeddedLogger) Debugf(format string, v ...interface{})  { l.log.Debugf(format, v...) }
+func (l *embeddedLogger) Tracef(format string, v ...interface{})  { l.log.Tracef(format, v...) }
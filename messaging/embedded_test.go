@@ -0,0 +1,47 @@
+package messaging
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddedServer(t *testing.T) {
+	log := logrus.NewEntry(logrus.New())
+
+	srv, err := NewEmbeddedServer(&EmbeddedConfig{Port: -1}, log)
+	require.NoError(t, err)
+	defer srv.Shutdown()
+
+	nc, err := srv.Connect()
+	require.NoError(t, err)
+	defer nc.Close()
+
+	sub, err := nc.SubscribeSync("test.subject")
+	require.NoError(t, err)
+
+	require.NoError(t, nc.Publish("test.subject", []byte("hello")))
+	require.NoError(t, nc.Flush())
+
+	msg, err := sub.NextMsg(time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(msg.Data))
+}
+
+func TestConfigureNatsConnectionWithEmbedded(t *testing.T) {
+	log := logrus.NewEntry(logrus.New())
+
+	nc, handle, err := ConfigureNatsConnection(&NatsConfig{
+		Embedded: &EmbeddedConfig{Port: -1},
+	}, log)
+	require.NoError(t, err)
+	require.NotNil(t, handle)
+	require.NotNil(t, handle.Embedded)
+	defer nc.Close()
+	defer handle.Close()
+
+	assert.True(t, nc.IsConnected())
+}
@@ -0,0 +1,49 @@
+package messaging
+
+import (
+	"strings"
+
+	"github.com/nats-io/nats"
+	"github.com/sirupsen/logrus"
+)
+
+// ReconnectHandler logs every time the connection re-establishes itself
+// after a disconnect, including how many times it has reconnected so far.
+func ReconnectHandler(log *logrus.Entry) nats.ConnHandler {
+	return func(conn *nats.Conn) {
+		log.WithFields(logrus.Fields{
+			"component":  "reconnect-handler",
+			"reconnects": conn.Stats().Reconnects,
+			"servers":    strings.Join(conn.Servers(), ","),
+		}).Info("Reconnected to nats")
+	}
+}
+
+// DisconnectErrHandler logs the error that caused a disconnect, if any.
+func DisconnectErrHandler(log *logrus.Entry) nats.ConnErrHandler {
+	return func(conn *nats.Conn, err error) {
+		l := log.WithField("component", "disconnect-handler")
+		if err != nil {
+			l = l.WithField("last_err", err.Error())
+		}
+		l.Warn("Disconnected from nats")
+	}
+}
+
+// ClosedHandler logs once the connection is closed and will not reconnect.
+func ClosedHandler(log *logrus.Entry) nats.ConnHandler {
+	return func(conn *nats.Conn) {
+		log.WithField("component", "closed-handler").Info("Nats connection closed")
+	}
+}
+
+// DiscoveredServersHandler logs whenever the cluster advertises new servers
+// to the client.
+func DiscoveredServersHandler(log *logrus.Entry) nats.ConnHandler {
+	return func(conn *nats.Conn) {
+		log.WithFields(logrus.Fields{
+			"component": "discovered-servers-handler",
+			"servers":   strings.Join(conn.DiscoveredServers(), ","),
+		}).Info("Discovered new nats servers")
+	}
+}
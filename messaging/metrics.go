@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nats-io/nats"
+	"github.com/sirupsen/logrus"
+)
+
+// defaultMetricsInterval is how often connection stats are published when
+// MetricsConfig.Interval is unset.
+const defaultMetricsInterval = 30 * time.Second
+
+// Interval controls how often StartMetricsReporter publishes stats; it
+// defaults to defaultMetricsInterval when zero.
+func (config *MetricsConfig) interval() time.Duration {
+	if config.Interval == 0 {
+		return defaultMetricsInterval
+	}
+	return config.Interval
+}
+
+// StartMetricsReporter launches a background goroutine that periodically
+// publishes connection stats (InMsgs, OutMsgs, Reconnects, and PendingMsgs
+// per subscription tracked on handle) to config.Subject, merged with the
+// configured default dimensions. handle may be nil, in which case
+// pending-message counts are omitted. Call the returned stop function to end
+// reporting - ConfigureNatsConnection registers it on the returned
+// *ConnHandle so callers can stop it via ConnHandle.Close.
+func StartMetricsReporter(nc *nats.Conn, config *MetricsConfig, handle *ConnHandle, log *logrus.Entry) func() {
+	if config == nil || config.Subject == "" {
+		return func() {}
+	}
+
+	ticker := time.NewTicker(config.interval())
+	done := make(chan struct{})
+
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				var subs []*nats.Subscription
+				if handle != nil {
+					subs = handle.Subscriptions()
+				}
+				publishMetrics(nc, config, subs, log)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func publishMetrics(nc *nats.Conn, config *MetricsConfig, subs []*nats.Subscription, log *logrus.Entry) {
+	stats := nc.Stats()
+
+	dims := map[string]interface{}{}
+	if config.Dimensions != nil {
+		for k, v := range *config.Dimensions {
+			dims[k] = v
+		}
+	}
+
+	dims["in_msgs"] = stats.InMsgs
+	dims["out_msgs"] = stats.OutMsgs
+	dims["reconnects"] = stats.Reconnects
+
+	pending := map[string]int{}
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		count, _, err := sub.Pending()
+		if err != nil {
+			continue
+		}
+		pending[sub.Subject] = count
+	}
+	dims["pending_msgs"] = pending
+
+	payload, err := json.Marshal(dims)
+	if err != nil {
+		log.WithError(err).Warn("Failed to marshal nats connection metrics")
+		return
+	}
+
+	if err := nc.Publish(config.Subject, payload); err != nil {
+		log.WithError(err).Warn("Failed to publish nats connection metrics")
+	}
+}
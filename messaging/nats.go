@@ -3,6 +3,7 @@ package messaging
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/nats-io/nats"
 	"github.com/sirupsen/logrus"
@@ -14,15 +15,21 @@ import (
 )
 
 type NatsConfig struct {
-	TLS           *tls.Config `mapstructure:"tls_conf"`
-	DiscoveryName string      `split_words:"true" mapstructure:"discovery_name"`
-	Servers       []string    `mapstructure:"servers"`
-	LogsSubject   string      `mapstructure:"log_subject"`
+	TLS              *tls.Config             `mapstructure:"tls_conf"`
+	Auth             *AuthConfig             `mapstructure:"auth"`
+	DiscoveryName    string                  `split_words:"true" mapstructure:"discovery_name"`
+	DiscoveryRefresh time.Duration           `split_words:"true" mapstructure:"discovery_refresh"`
+	Servers          []string                `mapstructure:"servers"`
+	LogsSubject      string                  `mapstructure:"log_subject"`
+	Redelivery       *ClientRedeliveryConfig `mapstructure:"redelivery"`
+	Metrics          *MetricsConfig          `mapstructure:"metrics"`
+	Embedded         *EmbeddedConfig         `mapstructure:"embedded"`
 }
 
 type MetricsConfig struct {
 	Subject    string                  `mapstructure:"subject"`
 	Dimensions *map[string]interface{} `mapstructure:"default_dims"`
+	Interval   time.Duration           `mapstructure:"interval"`
 }
 
 // ServerString will build the proper string for nats connect
@@ -42,18 +49,32 @@ func (config *NatsConfig) Fields() logrus.Fields {
 		f["cert_file"] = config.TLS.CertFile
 	}
 
+	if config.Auth != nil {
+		for k, v := range config.Auth.Fields() {
+			f[k] = v
+		}
+	}
+
 	return f
 }
 
-func ConfigureNatsConnection(config *NatsConfig, log *logrus.Entry) (*nats.Conn, error) {
+// ConfigureNatsConnection connects to nats per config and returns both the
+// connection and a *ConnHandle that owns the background goroutines
+// (metrics reporting, discovery refresh) started on its behalf. Callers
+// should call handle.Close() when they're done with the connection - tests
+// in particular, to avoid leaking those goroutines between cases.
+func ConfigureNatsConnection(config *NatsConfig, log *logrus.Entry) (*nats.Conn, *ConnHandle, error) {
 	if config == nil {
 		log.Debug("Skipping nats connection because there is no config")
-		return nil, nil
+		return nil, nil, nil
 	}
 
-	nc, err := ConnectToNats(config, ErrorHandler(log))
+	nc, embedded, err := ConnectToNats(config, log)
 	if err != nil {
-		return nil, err
+		if embedded != nil {
+			embedded.Shutdown()
+		}
+		return nil, nil, err
 	}
 
 	if config.LogsSubject != "" {
@@ -61,15 +82,49 @@ func ConfigureNatsConnection(config *NatsConfig, log *logrus.Entry) (*nats.Conn,
 		log.WithField("subject", config.LogsSubject).Debug("Configured nats hook for logrus")
 	}
 
-	return nc, nil
+	handle := newConnHandle()
+	handle.Embedded = embedded
+
+	if config.Metrics != nil {
+		handle.addStopFunc(StartMetricsReporter(nc, config.Metrics, handle, log))
+	}
+
+	handle.addStopFunc(StartDiscoveryDriftLogger(config, log))
+
+	return nc, handle, nil
 }
 
-// ConnectToNats will do a TLS connection to the nats servers specified
-func ConnectToNats(config *NatsConfig, errHandler nats.ErrHandler) (*nats.Conn, error) {
+// ConfigureRedelivery returns a handle for subscribing durable consumers
+// with client-side redelivery per config.Redelivery (see
+// ClientRedeliveryConfig - this is not JetStream). It is a no-op, returning
+// (nil, nil), when the config has no Redelivery section.
+func ConfigureRedelivery(config *NatsConfig, log *logrus.Entry) (*ClientRedeliveryConsumer, error) {
+	if config == nil || config.Redelivery == nil {
+		return nil, nil
+	}
+
+	return NewClientRedeliveryConsumer(config.Redelivery, log)
+}
+
+// ConnectToNats will do a TLS connection to the nats servers specified,
+// logging errors, reconnects, disconnects, and closures via log. When
+// config.Embedded is set, it starts an in-process server first and connects
+// to that instead, returning the server so the caller can shut it down.
+func ConnectToNats(config *NatsConfig, log *logrus.Entry) (*nats.Conn, *EmbeddedServer, error) {
+	var embedded *EmbeddedServer
+	if config.Embedded != nil {
+		var err error
+		embedded, err = NewEmbeddedServer(config.Embedded, log)
+		if err != nil {
+			return nil, nil, err
+		}
+		config.Servers = []string{embedded.ClientURL()}
+	}
+
 	if config.DiscoveryName != "" {
 		servers, err := discoverNatsURLs(config.DiscoveryName)
 		if err != nil {
-			return nil, err
+			return nil, embedded, err
 		}
 		config.Servers = servers
 	}
@@ -78,18 +133,35 @@ func ConnectToNats(config *NatsConfig, errHandler nats.ErrHandler) (*nats.Conn,
 	if config.TLS != nil {
 		tlsConfig, err := config.TLS.TLSConfig()
 		if err != nil {
-			return nil, err
+			return nil, embedded, err
 		}
 		if tlsConfig != nil {
 			options = append(options, nats.Secure(tlsConfig))
 		}
 	}
 
-	if errHandler != nil {
-		options = append(options, nats.ErrorHandler(errHandler))
+	if config.Auth != nil {
+		authOpts, err := config.Auth.options()
+		if err != nil {
+			return nil, embedded, err
+		}
+		options = append(options, authOpts...)
+	}
+
+	options = append(options,
+		nats.ErrorHandler(ErrorHandler(log)),
+		nats.ReconnectHandler(ReconnectHandler(log)),
+		nats.DisconnectErrHandler(DisconnectErrHandler(log)),
+		nats.ClosedHandler(ClosedHandler(log)),
+		nats.DiscoveredServersHandler(DiscoveredServersHandler(log)),
+	)
+
+	nc, err := nats.Connect(config.ServerString(), options...)
+	if err != nil {
+		return nil, embedded, err
 	}
 
-	return nats.Connect(config.ServerString(), options...)
+	return nc, embedded, nil
 }
 
 func ErrorHandler(log *logrus.Entry) nats.ErrHandler {
@@ -0,0 +1,179 @@
+package messaging
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats"
+	"github.com/sirupsen/logrus"
+)
+
+// ClientRedeliveryConfig configures a durable queue-group consumer with
+// client-side redelivery. This is NOT JetStream: the nats-io/nats client
+// this module is pinned to predates JetStream entirely (it only shipped
+// after the client was renamed to nats-io/nats.go), so there is no
+// server-side stream, no persistence, and no replay after a crash - every
+// unacked message tracked here lives only in this process's memory. All
+// this buys callers is in-memory "redeliver if not acked within AckWait",
+// nothing more. Pulling in nats-io/nats.go to get real JetStream is a
+// separate migration, not something this type can paper over.
+type ClientRedeliveryConfig struct {
+	Durable    string        `mapstructure:"durable"`
+	MaxDeliver int           `mapstructure:"max_deliver"`
+	AckWait    time.Duration `mapstructure:"ack_wait"`
+}
+
+// ClientRedeliveryConsumer tracks in-flight, unacknowledged deliveries for
+// one or more durable consumers and redelivers them if Msg.Ack isn't called
+// within AckWait. The pending map, guarded by mu, is the single source of
+// truth for whether a message is still outstanding - ack and a firing timer
+// both have to take mu before touching it, so whichever of the two runs
+// first wins and the other sees the map already updated. That's what
+// prevents an ack landing at the same moment as the AckWait deadline from
+// corrupting state; it does not prevent the handler from being invoked
+// again for a message that was acked a moment too late, which is inherent
+// to timeout-based at-least-once delivery, not a bug.
+type ClientRedeliveryConsumer struct {
+	cfg *ClientRedeliveryConfig
+	log *logrus.Entry
+
+	mu      sync.Mutex
+	pending map[uint64]*pendingMsg
+	nextID  uint64
+}
+
+type pendingMsg struct {
+	msg     *nats.Msg
+	deliver int
+	timer   *time.Timer
+}
+
+// Msg wraps a nats.Msg with the Ack method callers must invoke once they've
+// finished processing it; failing to do so within
+// ClientRedeliveryConfig.AckWait triggers redelivery.
+type Msg struct {
+	*nats.Msg
+
+	consumer *ClientRedeliveryConsumer
+	id       uint64
+}
+
+// Ack marks the message as processed, cancelling any pending redelivery.
+func (m *Msg) Ack() {
+	m.consumer.ack(m.id)
+}
+
+// NewClientRedeliveryConsumer validates cfg and returns a handle that can be
+// used to subscribe durable consumers with client-side redelivery.
+func NewClientRedeliveryConsumer(cfg *ClientRedeliveryConfig, log *logrus.Entry) (*ClientRedeliveryConsumer, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("no redelivery config provided")
+	}
+	if cfg.Durable == "" {
+		return nil, fmt.Errorf("redelivery config requires a durable consumer name")
+	}
+
+	return &ClientRedeliveryConsumer{
+		cfg:     cfg,
+		log:     log.WithField("durable", cfg.Durable),
+		pending: map[uint64]*pendingMsg{},
+	}, nil
+}
+
+// SubscribeOpt customizes a single Subscribe call, e.g. overriding the
+// durable name for that particular consumer.
+type SubscribeOpt func(*ClientRedeliveryConfig)
+
+// WithDurable overrides the durable consumer name configured on the
+// consumer for this subscription.
+func WithDurable(name string) SubscribeOpt {
+	return func(cfg *ClientRedeliveryConfig) {
+		cfg.Durable = name
+	}
+}
+
+// Subscribe attaches a durable queue-group consumer to subject and delivers
+// messages to handler. Messages that aren't acked within AckWait are
+// redelivered until they have been delivered MaxDeliver times in total
+// (the first delivery included), after which they're dropped and logged.
+func (c *ClientRedeliveryConsumer) Subscribe(nc *nats.Conn, subject string, handler func(*Msg), opts ...SubscribeOpt) (*nats.Subscription, error) {
+	cfg := *c.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Durable == "" {
+		return nil, fmt.Errorf("redelivery subscribe requires a durable consumer name")
+	}
+
+	sub, err := nc.QueueSubscribe(subject, cfg.Durable, func(msg *nats.Msg) {
+		c.deliver(&cfg, handler, msg)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+
+	c.log.WithField("subject", subject).Debug("Subscribed to durable consumer")
+
+	return sub, nil
+}
+
+func (c *ClientRedeliveryConsumer) deliver(cfg *ClientRedeliveryConfig, handler func(*Msg), msg *nats.Msg) {
+	c.mu.Lock()
+	c.nextID++
+	id := c.nextID
+
+	p := &pendingMsg{msg: msg}
+	if cfg.AckWait > 0 {
+		p.timer = time.AfterFunc(cfg.AckWait, func() { c.redeliver(cfg, handler, id) })
+	}
+	c.pending[id] = p
+	c.mu.Unlock()
+
+	handler(&Msg{Msg: msg, consumer: c, id: id})
+}
+
+func (c *ClientRedeliveryConsumer) redeliver(cfg *ClientRedeliveryConfig, handler func(*Msg), id uint64) {
+	c.mu.Lock()
+	p, ok := c.pending[id]
+	if !ok {
+		// Already acked (or already dropped) by the time this timer fired -
+		// nothing to redeliver.
+		c.mu.Unlock()
+		return
+	}
+
+	p.deliver++
+	if cfg.MaxDeliver > 0 && p.deliver >= cfg.MaxDeliver {
+		delete(c.pending, id)
+		c.mu.Unlock()
+		c.log.WithFields(logrus.Fields{
+			"subject":     p.msg.Subject,
+			"max_deliver": cfg.MaxDeliver,
+		}).Warn("Dropping message after exceeding max deliveries")
+		return
+	}
+
+	if cfg.AckWait > 0 {
+		p.timer = time.AfterFunc(cfg.AckWait, func() { c.redeliver(cfg, handler, id) })
+	}
+	msg := p.msg
+	c.mu.Unlock()
+
+	handler(&Msg{Msg: msg, consumer: c, id: id})
+}
+
+func (c *ClientRedeliveryConsumer) ack(id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, ok := c.pending[id]
+	if !ok {
+		return
+	}
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	delete(c.pending, id)
+}
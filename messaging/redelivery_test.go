@@ -0,0 +1,94 @@
+package messaging
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRedeliveryConsumer(t *testing.T, cfg *ClientRedeliveryConfig) *ClientRedeliveryConsumer {
+	c, err := NewClientRedeliveryConsumer(cfg, logrus.NewEntry(logrus.New()))
+	require.NoError(t, err)
+	return c
+}
+
+func TestRedeliverOnMissingAck(t *testing.T) {
+	c := newTestRedeliveryConsumer(t, &ClientRedeliveryConfig{
+		Durable: "test",
+		AckWait: 20 * time.Millisecond,
+	})
+
+	var deliveries int32
+	c.deliver(c.cfg, func(msg *Msg) {
+		atomic.AddInt32(&deliveries, 1)
+	}, &nats.Msg{Subject: "test.subject"})
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadInt32(&deliveries) >= 2
+	}, time.Second, 5*time.Millisecond, "expected at least one redelivery")
+}
+
+func TestAckStopsRedelivery(t *testing.T) {
+	c := newTestRedeliveryConsumer(t, &ClientRedeliveryConfig{
+		Durable: "test",
+		AckWait: 20 * time.Millisecond,
+	})
+
+	var deliveries int32
+	c.deliver(c.cfg, func(msg *Msg) {
+		atomic.AddInt32(&deliveries, 1)
+		msg.Ack()
+	}, &nats.Msg{Subject: "test.subject"})
+
+	time.Sleep(100 * time.Millisecond)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&deliveries))
+}
+
+func TestMaxDeliverDropsMessage(t *testing.T) {
+	c := newTestRedeliveryConsumer(t, &ClientRedeliveryConfig{
+		Durable:    "test",
+		AckWait:    10 * time.Millisecond,
+		MaxDeliver: 3,
+	})
+
+	var deliveries int32
+	c.deliver(c.cfg, func(msg *Msg) {
+		atomic.AddInt32(&deliveries, 1)
+	}, &nats.Msg{Subject: "test.subject"})
+
+	time.Sleep(200 * time.Millisecond)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&deliveries))
+}
+
+// TestAckRaceWithRedeliverTimer exercises an Ack landing at roughly the same
+// moment the AckWait timer fires - both take c.mu before touching the
+// pending map, so the map stays consistent either way. Run with -race to
+// confirm there's no data race on it.
+func TestAckRaceWithRedeliverTimer(t *testing.T) {
+	c := newTestRedeliveryConsumer(t, &ClientRedeliveryConfig{
+		Durable: "test",
+		AckWait: time.Millisecond,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		var ack func()
+		c.deliver(c.cfg, func(msg *Msg) {
+			ack = msg.Ack
+		}, &nats.Msg{Subject: "test.subject"})
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+			ack()
+		}()
+	}
+	wg.Wait()
+}